@@ -1,13 +1,11 @@
-package ytt
+package main
 
 import (
-	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -18,12 +16,18 @@ var videoRegexpList = []*regexp.Regexp{
 }
 
 var (
-	ErrInvalidCharactersInVideoID = errors.New("invalid characters in video id")
-	ErrVideoIDMinLength           = errors.New("the video id must be at least 10 characters long")
-	ErrTranscriptsDisabled        = errors.New("transcripts disabled")
-	ErrTranscriptsUnavailable     = errors.New("transcripts disabled or video unavailable")
-	ErrNoTranscriptFound          = errors.New("no transcript found for the given language codes")
-	ErrInvalidFormat              = errors.New("invalid captions tracks format")
+	ErrInvalidCharactersInVideoID  = errors.New("invalid characters in video id")
+	ErrVideoIDMinLength            = errors.New("the video id must be at least 10 characters long")
+	ErrTranscriptsDisabled         = errors.New("transcripts disabled")
+	ErrTranscriptsUnavailable      = errors.New("transcripts disabled or video unavailable")
+	ErrNoTranscriptFound           = errors.New("no transcript found for the given language codes")
+	ErrInvalidFormat               = errors.New("invalid captions tracks format")
+	ErrInvalidFormatName           = errors.New("invalid output format name")
+	ErrNotTranslatable             = errors.New("transcript is not translatable")
+	ErrTranslationLanguageNotFound = errors.New("no translation available for the given language code")
+	ErrNoCaptionSources            = errors.New("no caption sources to try")
+	ErrInvalidPlaylistURL          = errors.New("could not find a list= parameter in the playlist URL")
+	ErrInvalidChannelURL           = errors.New("could not find a channel ID or @handle in the channel URL")
 )
 
 // ExtractVideoID extracts the videoID from the given string for a YouTube URL.
@@ -55,43 +59,120 @@ const (
 // TranscriptList represents a list of transcripts for a YouTube video.
 type TranscriptList struct {
 	VideoID                    string
+	Title                      string
+	Author                     string
+	LengthSeconds              int
 	ManuallyCreatedTranscripts map[string]*Transcript
 	GeneratedTranscripts       map[string]*Transcript
 }
 
 // Transcript represents a transcript for a YouTube video.
 type Transcript struct {
-	VideoID      string
-	URL          string
-	Language     string
+	VideoID              string
+	URL                  string
+	Language             string
+	LanguageCode         string
+	IsGenerated          bool
+	IsTranslatable       bool
+	TranslationLanguages []TranslationLanguage
+
+	client *Client
+}
+
+// TranslationLanguage represents a language that a Transcript can be
+// translated into via Transcript.Translate.
+type TranslationLanguage struct {
 	LanguageCode string
-	IsGenerated  bool
+	Language     string
 }
 
 // TranscriptEntry represents a transcript entry for a YouTube video.
 type TranscriptEntry struct {
-	Text     string  `xml:",chardata"`
-	Start    float64 `xml:"start,attr"`
-	Duration float64 `xml:"dur,attr"`
+	Text     string
+	Start    float64
+	Duration float64
+
+	// Styles holds the <c> span classes (e.g. "colorE5E5E5") YouTube used to
+	// style parts of this entry's text, if any. Most transcripts don't carry
+	// styling and leave this nil.
+	Styles []string
+
+	// Words holds per-word timing when the entry was fetched with
+	// FetchOptions.Format set to FormatJSON3. It is nil otherwise.
+	Words []Word
 }
 
-// ListTranscripts lists the transcripts for the given videoID.
+// Word represents a single word and its timing within a TranscriptEntry,
+// available when a transcript is fetched in the JSON3 timedtext format.
+type Word struct {
+	Text     string
+	Start    float64
+	Duration float64
+}
+
+// ListTranscripts lists the transcripts for the given videoID, trying
+// defaultCaptionSources in order until one returns usable caption tracks.
 func ListTranscripts(videoID string) (*TranscriptList, error) {
-	html, err := fetchVideoHTML(videoID)
-	if err != nil {
-		return nil, err
+	return defaultClient.ListTranscriptsFrom(videoID, defaultCaptionSources...)
+}
+
+// ListTranscriptsFrom lists the transcripts for the given videoID using the
+// default client, trying each CaptionSource in order and returning the first
+// one that yields caption tracks. This lets callers (and tests) force a
+// particular source, e.g. a specific InnerTube client.
+func ListTranscriptsFrom(videoID string, sources ...CaptionSource) (*TranscriptList, error) {
+	return defaultClient.ListTranscriptsFrom(videoID, sources...)
+}
+
+// ListTranscripts lists the transcripts for the given videoID, trying
+// defaultCaptionSources in order until one returns usable caption tracks.
+func (c *Client) ListTranscripts(videoID string) (*TranscriptList, error) {
+	return c.ListTranscriptsFrom(videoID, defaultCaptionSources...)
+}
+
+// ListTranscriptsFrom lists the transcripts for the given videoID, trying
+// each CaptionSource in order and returning the first one that yields
+// caption tracks.
+func (c *Client) ListTranscriptsFrom(videoID string, sources ...CaptionSource) (*TranscriptList, error) {
+	if len(sources) == 0 {
+		return nil, ErrNoCaptionSources
 	}
 
-	captionsJSON, err := extractCaptionsJSON(html, videoID)
-	if err != nil {
-		return nil, err
+	lastErr := ErrNoCaptionSources
+
+	for _, source := range sources {
+		playerResponse, err := source.PlayerResponse(c, videoID)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if playerResponse.Captions == nil {
+			lastErr = ErrTranscriptsDisabled
+			continue
+		}
+
+		transcriptList, err := buildTranscriptList(videoID, playerResponse)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		for _, transcript := range transcriptList.ManuallyCreatedTranscripts {
+			transcript.client = c
+		}
+		for _, transcript := range transcriptList.GeneratedTranscripts {
+			transcript.client = c
+		}
+
+		return transcriptList, nil
 	}
 
-	return buildTranscriptList(videoID, captionsJSON)
+	return nil, lastErr
 }
 
-func fetchVideoHTML(videoID string) (string, error) {
-	resp, err := http.Get(fmt.Sprintf(watchURL, videoID))
+func fetchVideoHTML(c *Client, videoID string) (string, error) {
+	resp, err := c.Get(fmt.Sprintf(watchURL, videoID))
 	if err != nil {
 		return "", err
 	}
@@ -105,71 +186,84 @@ func fetchVideoHTML(videoID string) (string, error) {
 	return string(body), nil
 }
 
-func extractCaptionsJSON(html, videoID string) (map[string]interface{}, error) {
-	parts := strings.Split(html, `"captions":`)
-	if len(parts) <= 1 {
-		return nil, ErrTranscriptsUnavailable
-	}
-
-	jsonPart := strings.Split(parts[1], `,"videoDetails"`)[0]
-	jsonPart = strings.ReplaceAll(jsonPart, "\n", "")
-
-	var result map[string]interface{}
-	err := json.Unmarshal([]byte(jsonPart), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	captionsJSON, ok := result["playerCaptionsTracklistRenderer"].(map[string]interface{})
-	if !ok {
-		return nil, ErrTranscriptsDisabled
-	}
-
-	return captionsJSON, nil
-}
-
-func buildTranscriptList(videoID string, captionsJSON map[string]interface{}) (*TranscriptList, error) {
+func buildTranscriptList(videoID string, playerResponse *PlayerResponse) (*TranscriptList, error) {
 	manualTranscripts := make(map[string]*Transcript)
 	generatedTranscripts := make(map[string]*Transcript)
 
-	captionTracks, ok := captionsJSON["captionTracks"].([]interface{})
-	if !ok {
+	renderer := playerResponse.Captions.PlayerCaptionsTracklistRenderer
+	if len(renderer.CaptionTracks) == 0 {
 		return nil, ErrInvalidFormat
 	}
 
-	for _, captionTrack := range captionTracks {
-		track, _ := captionTrack.(map[string]interface{})
-		languageCode, _ := track["languageCode"].(string)
-		baseURL, _ := track["baseUrl"].(string)
-		name, _ := track["name"].(map[string]interface{})
-		simpleText, _ := name["simpleText"].(string)
-		kind, _ := track["kind"].(string)
+	translationLanguages := buildTranslationLanguages(renderer.TranslationLanguages)
 
+	for _, track := range renderer.CaptionTracks {
 		transcript := &Transcript{
-			VideoID:      videoID,
-			URL:          baseURL,
-			Language:     simpleText,
-			LanguageCode: languageCode,
-			IsGenerated:  kind == "asr",
+			VideoID:              videoID,
+			URL:                  track.BaseURL,
+			Language:             track.Name.SimpleText,
+			LanguageCode:         track.LanguageCode,
+			IsGenerated:          track.Kind == "asr",
+			IsTranslatable:       track.IsTranslatable,
+			TranslationLanguages: translationLanguages,
 		}
 
-		if kind == "asr" {
-			generatedTranscripts[languageCode] = transcript
+		if track.Kind == "asr" {
+			generatedTranscripts[track.LanguageCode] = transcript
 		} else {
-			manualTranscripts[languageCode] = transcript
+			manualTranscripts[track.LanguageCode] = transcript
 		}
 	}
 
+	lengthSeconds, _ := strconv.Atoi(playerResponse.VideoDetails.LengthSeconds)
+
 	return &TranscriptList{
 		VideoID:                    videoID,
+		Title:                      playerResponse.VideoDetails.Title,
+		Author:                     playerResponse.VideoDetails.Author,
+		LengthSeconds:              lengthSeconds,
 		ManuallyCreatedTranscripts: manualTranscripts,
 		GeneratedTranscripts:       generatedTranscripts,
 	}, nil
 }
 
-// Fetch fetches the transcript from the transcript URL.
+func buildTranslationLanguages(rawLanguages []translationLanguageRaw) []TranslationLanguage {
+	if len(rawLanguages) == 0 {
+		return nil
+	}
+
+	languages := make([]TranslationLanguage, 0, len(rawLanguages))
+	for _, rawLanguage := range rawLanguages {
+		languages = append(languages, TranslationLanguage{
+			LanguageCode: rawLanguage.LanguageCode,
+			Language:     rawLanguage.LanguageName.SimpleText,
+		})
+	}
+
+	return languages
+}
+
+// Fetch fetches the transcript from the transcript URL using the default
+// XML timedtext format. Use FetchWithOptions to request JSON3 (word-level
+// timing) or to merge consecutive entries into paragraphs.
 func (t *Transcript) Fetch() ([]TranscriptEntry, error) {
-	resp, err := http.Get(t.URL)
+	return t.FetchWithOptions(FetchOptions{})
+}
+
+// FetchWithOptions fetches the transcript from the transcript URL, applying
+// opts.Format and opts.MergeConsecutive.
+func (t *Transcript) FetchWithOptions(opts FetchOptions) ([]TranscriptEntry, error) {
+	client := t.client
+	if client == nil {
+		client = defaultClient
+	}
+
+	url := t.URL
+	if opts.Format == FormatJSON3 {
+		url += "&fmt=json3"
+	}
+
+	resp, err := client.Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -180,29 +274,49 @@ func (t *Transcript) Fetch() ([]TranscriptEntry, error) {
 		return nil, err
 	}
 
-	return parseTranscript(string(body))
-}
-
-func parseTranscript(xmlData string) ([]TranscriptEntry, error) {
-	var transcript struct {
-		Entries []TranscriptEntry `xml:"text"`
+	var entries []TranscriptEntry
+	if opts.Format == FormatJSON3 {
+		entries, err = parseJSON3Transcript(string(body))
+	} else {
+		entries, err = parseXMLTranscript(string(body))
 	}
-
-	err := xml.Unmarshal([]byte(xmlData), &transcript)
 	if err != nil {
 		return nil, err
 	}
 
-	for i := range transcript.Entries {
-		transcript.Entries[i].Text = removeHTMLTags(transcript.Entries[i].Text)
+	if opts.MergeConsecutive > 0 {
+		entries = MergeConsecutive(entries, opts.MergeConsecutive)
 	}
 
-	return transcript.Entries, nil
+	return entries, nil
 }
 
-func removeHTMLTags(text string) string {
-	re := regexp.MustCompile("<[^>]*>")
-	return re.ReplaceAllString(text, "")
+// Translate returns a new Transcript for the same video, translated into the
+// given language code via YouTube's translatable caption tracks. It returns
+// ErrNotTranslatable if the transcript does not support translation, and
+// ErrTranslationLanguageNotFound if languageCode is not offered.
+func (t *Transcript) Translate(languageCode string) (*Transcript, error) {
+	if !t.IsTranslatable {
+		return nil, ErrNotTranslatable
+	}
+
+	found := false
+	for _, lang := range t.TranslationLanguages {
+		if lang.LanguageCode == languageCode {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, ErrTranslationLanguageNotFound
+	}
+
+	translated := *t
+	translated.URL = t.URL + "&tlang=" + languageCode
+	translated.LanguageCode = languageCode
+	translated.IsGenerated = true
+
+	return &translated, nil
 }
 
 // FindTranscript finds the first transcript that matches the language codes.
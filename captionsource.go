@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// CaptionSource knows how to retrieve the player response (and therefore the
+// available caption tracks) for a video via a particular strategy. Several
+// sources are tried in order by ListTranscripts since YouTube doesn't
+// consistently expose playerCaptionsTracklistRenderer on the watch page for
+// every video.
+type CaptionSource interface {
+	PlayerResponse(c *Client, videoID string) (*PlayerResponse, error)
+}
+
+// defaultCaptionSources is the chain tried by ListTranscripts: the watch page
+// first, falling back to the InnerTube player endpoint impersonating an
+// Android client, which YouTube serves captions for even when the watch page
+// hides them (age-gated, region-restricted, or A/B tested videos).
+var defaultCaptionSources = []CaptionSource{
+	WatchPageSource{},
+	InnerTubeSource{ClientName: "ANDROID", ClientVersion: "19.09.37"},
+}
+
+// WatchPageSource fetches the player response embedded in the watch page
+// HTML as ytInitialPlayerResponse.
+type WatchPageSource struct{}
+
+// PlayerResponse implements CaptionSource.
+func (WatchPageSource) PlayerResponse(c *Client, videoID string) (*PlayerResponse, error) {
+	html, err := fetchVideoHTML(c, videoID)
+	if err != nil {
+		return nil, err
+	}
+	return extractPlayerResponse(html)
+}
+
+const innertubePlayerURL = "https://www.youtube.com/youtubei/v1/player"
+
+// InnerTubeSource fetches the player response from YouTube's InnerTube
+// `player` endpoint, presenting itself as ClientName/ClientVersion. This is
+// how modern youtube-dl-style tools keep working despite watch-page changes.
+type InnerTubeSource struct {
+	ClientName    string
+	ClientVersion string
+	HL            string // interface language, defaults to "en"
+	GL            string // geolocation, defaults to "US"
+}
+
+// PlayerResponse implements CaptionSource.
+func (s InnerTubeSource) PlayerResponse(c *Client, videoID string) (*PlayerResponse, error) {
+	hl := s.HL
+	if hl == "" {
+		hl = "en"
+	}
+	gl := s.GL
+	if gl == "" {
+		gl = "US"
+	}
+
+	requestBody := map[string]interface{}{
+		"videoId": videoID,
+		"context": map[string]interface{}{
+			"client": map[string]interface{}{
+				"clientName":    s.ClientName,
+				"clientVersion": s.ClientVersion,
+				"hl":            hl,
+				"gl":            gl,
+			},
+		},
+	}
+
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Post(innertubePlayerURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("innertube player endpoint returned status %d", resp.StatusCode)
+	}
+
+	var playerResponse PlayerResponse
+	if err := json.Unmarshal(respBody, &playerResponse); err != nil {
+		return nil, err
+	}
+
+	return &playerResponse, nil
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClientOptions configures a Client.
+type ClientOptions struct {
+	// HTTPClient, if set, is used instead of constructing one from the
+	// remaining options. CookiesPath, ProxyURL and Timeout are ignored when
+	// this is set.
+	HTTPClient *http.Client
+
+	// CookiesPath is the path to a cookies.txt file in Netscape format, as
+	// exported by browser cookie-export extensions. It lets requests carry
+	// cookies for age-gated videos that require a signed-in session.
+	CookiesPath string
+
+	// UserAgent overrides the User-Agent header sent with every request.
+	UserAgent string
+
+	// ProxyURL routes requests through the given proxy, e.g.
+	// "http://localhost:8080".
+	ProxyURL string
+
+	// Timeout bounds every request made by the client. Zero means no timeout.
+	Timeout time.Duration
+}
+
+// Client fetches video pages and transcripts on behalf of the ytt package.
+// The zero value is not usable; construct one with NewClient.
+type Client struct {
+	httpClient *http.Client
+	userAgent  string
+}
+
+// defaultClient is used by the package-level ListTranscripts,
+// ListTranscriptsFrom and Transcript.Fetch so existing callers don't need to
+// construct a Client themselves.
+var defaultClient = mustNewClient(ClientOptions{})
+
+func mustNewClient(opts ClientOptions) *Client {
+	c, err := NewClient(opts)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NewClient builds a Client from opts. Unless opts.HTTPClient is set, a
+// CONSENT cookie is always set on the resulting cookie jar so requests
+// bypass YouTube's EU consent redirect, which otherwise causes
+// ListTranscripts to fail with ErrTranscriptsUnavailable. A caller-supplied
+// HTTPClient is used as is, without that cookie.
+func NewClient(opts ClientOptions) (*Client, error) {
+	if opts.HTTPClient != nil {
+		return &Client{httpClient: opts.HTTPClient, userAgent: opts.UserAgent}, nil
+	}
+
+	jar, err := newCookieJar(opts.CookiesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Jar:     jar,
+		Timeout: opts.Timeout,
+	}
+
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		httpClient.Transport = &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+	}
+
+	return &Client{httpClient: httpClient, userAgent: opts.UserAgent}, nil
+}
+
+func newCookieJar(cookiesPath string) (http.CookieJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if cookiesPath != "" {
+		cookies, err := parseNetscapeCookiesFile(cookiesPath)
+		if err != nil {
+			return nil, err
+		}
+		for domain, domainCookies := range cookies {
+			jar.SetCookies(&url.URL{Scheme: "https", Host: domain}, domainCookies)
+		}
+	}
+
+	jar.SetCookies(&url.URL{Scheme: "https", Host: "www.youtube.com"}, []*http.Cookie{
+		{Name: "CONSENT", Value: "YES+cb", Domain: ".youtube.com", Path: "/"},
+	})
+
+	return jar, nil
+}
+
+// parseNetscapeCookiesFile parses a cookies.txt file in the Netscape format
+// used by browser cookie-export extensions, grouping the resulting cookies
+// by domain.
+func parseNetscapeCookiesFile(path string) (map[string][]*http.Cookie, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cookies := make(map[string][]*http.Cookie)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			continue
+		}
+
+		domain := strings.TrimPrefix(fields[0], ".")
+		expires, _ := strconv.ParseInt(fields[4], 10, 64)
+
+		cookie := &http.Cookie{
+			Domain:  fields[0],
+			Path:    fields[2],
+			Secure:  fields[3] == "TRUE",
+			Name:    fields[5],
+			Value:   fields[6],
+			Expires: time.Unix(expires, 0),
+		}
+
+		cookies[domain] = append(cookies[domain], cookie)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cookies, nil
+}
+
+// Get performs an HTTP GET, applying the client's User-Agent if set.
+func (c *Client) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+// Post performs an HTTP POST, applying the client's User-Agent if set.
+func (c *Client) Post(url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.do(req)
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	return c.httpClient.Do(req)
+}
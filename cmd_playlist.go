@@ -0,0 +1,168 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// runPlaylist implements the `ytt playlist <url>` subcommand: it fetches
+// transcripts for every video listed on a playlist or channel page and
+// writes each to its own file in an output directory.
+func runPlaylist(args []string) {
+	fs := flag.NewFlagSet("playlist", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("%s playlist <playlist_or_channel_url>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+
+	outDir := fs.String("o", ".", "Output directory for transcript files")
+	lang := fs.String("lang", "en", "Language code for the desired transcript")
+	format := fs.String("format", "text", "Output format: text, srt, vtt, or json")
+	workers := fs.Int("workers", 4, "Number of videos to fetch concurrently")
+	channel := fs.Bool("channel", false, "Treat the argument as a channel ID instead of a playlist ID")
+	cookies := fs.String("cookies", "", "Path to a cookies.txt file (Netscape format) for age-restricted videos")
+	proxy := fs.String("proxy", "", "Proxy URL to route requests through")
+	userAgent := fs.String("user-agent", "", "Custom User-Agent header")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 {
+		printFancyError("playlist or channel URL is required")
+		os.Exit(1)
+	}
+
+	if *workers < 1 {
+		*workers = 1
+	}
+
+	client, err := NewClient(ClientOptions{
+		CookiesPath: *cookies,
+		ProxyURL:    *proxy,
+		UserAgent:   *userAgent,
+	})
+	if err != nil {
+		printFancyError(fmt.Sprintf("failed to create client: %v", err))
+		os.Exit(1)
+	}
+
+	formatter, err := NewFormatter(*format)
+	if err != nil {
+		printFancyError(fmt.Sprintf("invalid -format %q", *format))
+		os.Exit(1)
+	}
+
+	var videoIDs []string
+	if *channel {
+		channelID, err := ExtractChannelID(fs.Arg(0))
+		if err != nil {
+			printFancyError(fmt.Sprintf("failed to extract channel ID: %v", err))
+			os.Exit(1)
+		}
+		videoIDs, err = client.ListChannelVideos(channelID)
+		if err != nil {
+			printFancyError(fmt.Sprintf("failed to list videos: %v", err))
+			os.Exit(1)
+		}
+	} else {
+		playlistID, err := ExtractPlaylistID(fs.Arg(0))
+		if err != nil {
+			printFancyError(fmt.Sprintf("failed to extract playlist ID: %v", err))
+			os.Exit(1)
+		}
+		videoIDs, err = client.ListPlaylistVideos(playlistID)
+		if err != nil {
+			printFancyError(fmt.Sprintf("failed to list videos: %v", err))
+			os.Exit(1)
+		}
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		printFancyError(fmt.Sprintf("failed to create output directory: %v", err))
+		os.Exit(1)
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for videoID := range jobs {
+				if err := fetchVideoToFile(client, videoID, *outDir, *lang, formatter); err != nil {
+					mu.Lock()
+					printFancyError(fmt.Sprintf("%s: %v", videoID, err))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, videoID := range videoIDs {
+		jobs <- videoID
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// fetchVideoToFile fetches the transcript for videoID in the given language
+// and writes it to <outDir>/<videoID>.<ext>, skipping videos that have
+// already been downloaded so a playlist fetch can be resumed.
+func fetchVideoToFile(client *Client, videoID, outDir, lang string, formatter Formatter) error {
+	outPath := filepath.Join(outDir, videoID+"."+formatExtension(formatter))
+	if _, err := os.Stat(outPath); err == nil {
+		fmt.Printf("%s: already downloaded, skipping\n", videoID)
+		return nil
+	}
+
+	transcriptList, err := client.ListTranscripts(videoID)
+	if err != nil {
+		return fmt.Errorf("failed to list transcripts: %w", err)
+	}
+
+	transcript, err := transcriptList.FindTranscript(lang)
+	if err != nil {
+		return fmt.Errorf("no transcript found for language code '%s': %w", lang, err)
+	}
+
+	entries, err := transcript.Fetch()
+	if err != nil {
+		return fmt.Errorf("failed to fetch transcript: %w", err)
+	}
+
+	for i := range entries {
+		entries[i].Text = html.UnescapeString(entries[i].Text)
+	}
+
+	output, err := formatter.Format(entries)
+	if err != nil {
+		return fmt.Errorf("failed to format transcript: %w", err)
+	}
+
+	if err := os.WriteFile(outPath, []byte(output), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	fmt.Printf("%s: wrote %s\n", videoID, outPath)
+	return nil
+}
+
+func formatExtension(formatter Formatter) string {
+	switch formatter.(type) {
+	case SRTFormatter:
+		return "srt"
+	case WebVTTFormatter:
+		return "vtt"
+	case JSONFormatter:
+		return "json"
+	default:
+		return "txt"
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Formatter renders a list of transcript entries into a specific output format.
+type Formatter interface {
+	Format(entries []TranscriptEntry) (string, error)
+}
+
+// Formatters maps the names accepted by the -format flag to their Formatter.
+var Formatters = map[string]Formatter{
+	"srt":  SRTFormatter{},
+	"vtt":  WebVTTFormatter{},
+	"json": JSONFormatter{},
+	"text": TextFormatter{},
+}
+
+// NewFormatter returns the Formatter registered under name, or
+// ErrInvalidFormatName if name is not one of Formatters.
+func NewFormatter(name string) (Formatter, error) {
+	f, ok := Formatters[name]
+	if !ok {
+		return nil, ErrInvalidFormatName
+	}
+	return f, nil
+}
+
+// SRTFormatter renders entries as SubRip (.srt) subtitles.
+type SRTFormatter struct{}
+
+// Format implements Formatter.
+func (SRTFormatter) Format(entries []TranscriptEntry) (string, error) {
+	var sb strings.Builder
+	for i, entry := range entries {
+		fmt.Fprintf(&sb, "%d\n", i+1)
+		fmt.Fprintf(&sb, "%s --> %s\n", formatSRTTimestamp(entry.Start), formatSRTTimestamp(entry.Start+entry.Duration))
+		sb.WriteString(wrapText(escapeCueText(entry.Text)))
+		sb.WriteString("\n\n")
+	}
+	return sb.String(), nil
+}
+
+func formatSRTTimestamp(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second))
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	secs := d / time.Second
+	d -= secs * time.Second
+	millis := d / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, secs, millis)
+}
+
+// WebVTTFormatter renders entries as WebVTT (.vtt) subtitles.
+type WebVTTFormatter struct{}
+
+// Format implements Formatter.
+func (WebVTTFormatter) Format(entries []TranscriptEntry) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	for i, entry := range entries {
+		fmt.Fprintf(&sb, "%d\n", i+1)
+		fmt.Fprintf(&sb, "%s --> %s\n", formatVTTTimestamp(entry.Start), formatVTTTimestamp(entry.Start+entry.Duration))
+		sb.WriteString(wrapText(escapeCueText(entry.Text)))
+		sb.WriteString("\n\n")
+	}
+	return sb.String(), nil
+}
+
+func formatVTTTimestamp(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second))
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	secs := d / time.Second
+	d -= secs * time.Second
+	millis := d / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", hours, minutes, secs, millis)
+}
+
+// JSONFormatter renders entries as a JSON array.
+type JSONFormatter struct{}
+
+// Format implements Formatter.
+func (JSONFormatter) Format(entries []TranscriptEntry) (string, error) {
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// TextFormatter renders entries as plain text, each entry's text followed by
+// a newline. Line breaks inside an entry's text are kept as they are.
+type TextFormatter struct{}
+
+// Format implements Formatter.
+func (TextFormatter) Format(entries []TranscriptEntry) (string, error) {
+	var sb strings.Builder
+	for _, entry := range entries {
+		sb.WriteString(entry.Text)
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// escapeCueText escapes the markup-significant characters SRT and WebVTT
+// both treat as the start of a tag, so cue text containing a literal "&" or
+// "<" (e.g. left over from an unescaped transcript) renders as plain text
+// instead of producing invalid markup.
+func escapeCueText(text string) string {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;")
+	return replacer.Replace(text)
+}
+
+// wrapText wraps text to at most 2 lines for subtitle formats, splitting on
+// whitespace so cues stay readable.
+func wrapText(text string) string {
+	const maxLineLen = 42
+	const maxLines = 2
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return text
+	}
+
+	var lines []string
+	var current string
+	for _, word := range words {
+		if current == "" {
+			current = word
+			continue
+		}
+		if len(lines) < maxLines-1 && len(current)+1+len(word) > maxLineLen {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current += " " + word
+	}
+	lines = append(lines, current)
+
+	return strings.Join(lines, "\n")
+}
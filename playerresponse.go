@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// PlayerResponse models the subset of YouTube's ytInitialPlayerResponse
+// object that ytt cares about.
+type PlayerResponse struct {
+	VideoDetails VideoDetails `json:"videoDetails"`
+	Captions     *Captions    `json:"captions"`
+}
+
+// VideoDetails holds video metadata exposed alongside captions in the
+// player response.
+type VideoDetails struct {
+	VideoID       string `json:"videoId"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	LengthSeconds string `json:"lengthSeconds"`
+}
+
+// Captions models the "captions" key of the player response.
+type Captions struct {
+	PlayerCaptionsTracklistRenderer PlayerCaptionsTracklistRenderer `json:"playerCaptionsTracklistRenderer"`
+}
+
+// PlayerCaptionsTracklistRenderer models the renderer that lists the
+// available caption tracks and translation languages for a video.
+type PlayerCaptionsTracklistRenderer struct {
+	CaptionTracks        []CaptionTrack           `json:"captionTracks"`
+	TranslationLanguages []translationLanguageRaw `json:"translationLanguages"`
+}
+
+// CaptionTrack models a single entry in captionTracks.
+type CaptionTrack struct {
+	BaseURL string `json:"baseUrl"`
+	Name    struct {
+		SimpleText string `json:"simpleText"`
+	} `json:"name"`
+	LanguageCode   string `json:"languageCode"`
+	Kind           string `json:"kind"`
+	IsTranslatable bool   `json:"isTranslatable"`
+}
+
+type translationLanguageRaw struct {
+	LanguageCode string `json:"languageCode"`
+	LanguageName struct {
+		SimpleText string `json:"simpleText"`
+	} `json:"languageName"`
+}
+
+const playerResponseMarker = "ytInitialPlayerResponse"
+
+// extractPlayerResponse locates the `ytInitialPlayerResponse = {...};`
+// assignment in the watch page HTML and unmarshals it into a PlayerResponse.
+// Unlike splitting on neighbouring field names, this scans the JSON object
+// brace by brace (tracking string literals and escapes) so it keeps working
+// regardless of how YouTube orders or nests the surrounding fields.
+func extractPlayerResponse(html string) (*PlayerResponse, error) {
+	jsonPart, err := extractBalancedJSONObject(html, playerResponseMarker)
+	if err != nil {
+		return nil, err
+	}
+
+	var playerResponse PlayerResponse
+	if err := json.Unmarshal([]byte(jsonPart), &playerResponse); err != nil {
+		return nil, err
+	}
+
+	return &playerResponse, nil
+}
+
+// extractBalancedJSONObject finds the JSON object assigned to marker (i.e.
+// `<marker> = {...};`) and returns its full text, scanning brace depth while
+// respecting string literals and escape sequences. It anchors on the first
+// occurrence of marker that is actually followed by an `=` assignment,
+// skipping over guard checks like `if(!window.ytInitialPlayerResponse)`
+// that merely reference the name.
+func extractBalancedJSONObject(html, marker string) (string, error) {
+	start, err := findAssignedObjectStart(html, marker)
+	if err != nil {
+		return "", err
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+
+	for i := start; i < len(html); i++ {
+		c := html[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return html[start : i+1], nil
+			}
+		}
+	}
+
+	return "", ErrTranscriptsUnavailable
+}
+
+// findAssignedObjectStart scans html for an occurrence of marker that is
+// followed (after optional whitespace) by an `=`, and returns the index of
+// the `{` that opens the assigned object. Occurrences of marker that aren't
+// actually assignments (e.g. a preceding `if(!window.marker)` guard) are
+// skipped.
+func findAssignedObjectStart(html, marker string) (int, error) {
+	searchFrom := 0
+	for {
+		idx := strings.Index(html[searchFrom:], marker)
+		if idx == -1 {
+			return 0, ErrTranscriptsUnavailable
+		}
+		afterMarker := searchFrom + idx + len(marker)
+
+		i := afterMarker
+		for i < len(html) && (html[i] == ' ' || html[i] == '\t' || html[i] == '\n' || html[i] == '\r') {
+			i++
+		}
+
+		if i >= len(html) || html[i] != '=' {
+			searchFrom = afterMarker
+			continue
+		}
+
+		start := strings.IndexByte(html[i:], '{')
+		if start == -1 {
+			return 0, ErrTranscriptsUnavailable
+		}
+		return i + start, nil
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	playlistURL      = "https://www.youtube.com/playlist?list=%s"
+	channelURL       = "https://www.youtube.com/channel/%s/videos"
+	channelHandleURL = "https://www.youtube.com/%s/videos"
+)
+
+var videoIDInListRegexp = regexp.MustCompile(`"videoId":"([^"]{11})"`)
+
+// ExtractPlaylistID extracts the playlist ID from a playlist URL's list=
+// query parameter, or returns the input unchanged if it doesn't look like a
+// URL, mirroring how ExtractVideoID normalizes a single-video URL or ID.
+func ExtractPlaylistID(playlistID string) (string, error) {
+	if !strings.Contains(playlistID, "://") {
+		return playlistID, nil
+	}
+
+	u, err := url.Parse(playlistID)
+	if err != nil {
+		return "", ErrInvalidPlaylistURL
+	}
+
+	list := u.Query().Get("list")
+	if list == "" {
+		return "", ErrInvalidPlaylistURL
+	}
+
+	return list, nil
+}
+
+// ExtractChannelID extracts the channel ID or @handle from a channel URL
+// (accepting /channel/<id>, /c/<id>, /user/<id>, and /@<handle> paths), or
+// returns the input unchanged if it doesn't look like a URL.
+func ExtractChannelID(channelID string) (string, error) {
+	if !strings.Contains(channelID, "://") {
+		return channelID, nil
+	}
+
+	u, err := url.Parse(channelID)
+	if err != nil {
+		return "", ErrInvalidChannelURL
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) >= 1 && strings.HasPrefix(parts[0], "@") {
+		return parts[0], nil
+	}
+	if len(parts) >= 2 && (parts[0] == "channel" || parts[0] == "c" || parts[0] == "user") {
+		return parts[1], nil
+	}
+
+	return "", ErrInvalidChannelURL
+}
+
+// ListPlaylistVideos returns the distinct video IDs found in the given
+// YouTube playlist's page HTML, in first-seen order. Only that one page is
+// read; videos that the page loads later through continuation requests are
+// not included.
+func ListPlaylistVideos(playlistID string) ([]string, error) {
+	return defaultClient.ListPlaylistVideos(playlistID)
+}
+
+// ListChannelVideos returns the video IDs listed on the given channel's
+// videos tab by scraping the channel page HTML.
+func ListChannelVideos(channelID string) ([]string, error) {
+	return defaultClient.ListChannelVideos(channelID)
+}
+
+// ListPlaylistVideos returns the distinct video IDs found in the given
+// YouTube playlist's page HTML, in first-seen order. Only that one page is
+// read; videos that the page loads later through continuation requests are
+// not included.
+func (c *Client) ListPlaylistVideos(playlistID string) ([]string, error) {
+	html, err := fetchHTML(c, fmt.Sprintf(playlistURL, playlistID))
+	if err != nil {
+		return nil, err
+	}
+	return extractVideoIDs(html), nil
+}
+
+// ListChannelVideos returns the video IDs listed on the given channel's
+// videos tab by scraping the channel page HTML.
+func (c *Client) ListChannelVideos(channelID string) ([]string, error) {
+	url := fmt.Sprintf(channelURL, channelID)
+	if strings.HasPrefix(channelID, "@") {
+		url = fmt.Sprintf(channelHandleURL, channelID)
+	}
+
+	html, err := fetchHTML(c, url)
+	if err != nil {
+		return nil, err
+	}
+	return extractVideoIDs(html), nil
+}
+
+func fetchHTML(c *Client, url string) (string, error) {
+	resp, err := c.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return string(body), nil
+}
+
+// extractVideoIDs pulls out every distinct videoId referenced in the page,
+// preserving first-seen order.
+func extractVideoIDs(html string) []string {
+	matches := videoIDInListRegexp.FindAllStringSubmatch(html, -1)
+
+	seen := make(map[string]bool, len(matches))
+	videoIDs := make([]string, 0, len(matches))
+	for _, match := range matches {
+		videoID := match[1]
+		if seen[videoID] {
+			continue
+		}
+		seen[videoID] = true
+		videoIDs = append(videoIDs, videoID)
+	}
+
+	return videoIDs
+}
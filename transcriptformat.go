@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimedTextFormat selects which wire format FetchWithOptions requests from
+// YouTube's timedtext endpoint.
+type TimedTextFormat int
+
+const (
+	// FormatXML is YouTube's legacy timedtext XML format. It carries entry
+	// and <c> span timing but no per-word timing.
+	FormatXML TimedTextFormat = iota
+	// FormatJSON3 is YouTube's `fmt=json3` timedtext format. It carries
+	// per-word timing, surfaced via TranscriptEntry.Words.
+	FormatJSON3
+)
+
+// FetchOptions configures Transcript.FetchWithOptions.
+type FetchOptions struct {
+	// Format selects the timedtext wire format. The zero value is FormatXML.
+	Format TimedTextFormat
+
+	// MergeConsecutive, if positive, merges contiguous entries whose gap is
+	// smaller than this duration into single paragraph entries, which is
+	// useful when feeding a transcript to an LLM. Overlapping entries are
+	// not merged.
+	MergeConsecutive time.Duration
+}
+
+// parseXMLTranscript parses YouTube's legacy timedtext XML format, keeping
+// <br/> elements as segment breaks and <c> spans as style metadata instead
+// of stripping every tag down to bare text.
+func parseXMLTranscript(xmlData string) ([]TranscriptEntry, error) {
+	decoder := xml.NewDecoder(strings.NewReader(xmlData))
+
+	var entries []TranscriptEntry
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "text" {
+			continue
+		}
+
+		entry, err := parseTextElement(decoder, start)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// parseTextElement consumes tokens up to and including the closing </text>
+// matching start, collecting character data, <br/> breaks, and <c> span
+// classes along the way.
+func parseTextElement(decoder *xml.Decoder, start xml.StartElement) (TranscriptEntry, error) {
+	var entry TranscriptEntry
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "start":
+			entry.Start, _ = strconv.ParseFloat(attr.Value, 64)
+		case "dur":
+			entry.Duration, _ = strconv.ParseFloat(attr.Value, 64)
+		}
+	}
+
+	seenStyles := make(map[string]bool)
+	var sb strings.Builder
+
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return entry, err
+		}
+
+		switch t := tok.(type) {
+		case xml.CharData:
+			sb.Write(t)
+		case xml.StartElement:
+			if t.Name.Local == "br" {
+				sb.WriteString("\n")
+			}
+			if t.Name.Local == "c" {
+				for _, attr := range t.Attr {
+					if attr.Name.Local != "class" || seenStyles[attr.Value] {
+						continue
+					}
+					seenStyles[attr.Value] = true
+					entry.Styles = append(entry.Styles, attr.Value)
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "text" {
+				entry.Text = sb.String()
+				return entry, nil
+			}
+		}
+	}
+}
+
+type json3Transcript struct {
+	Events []json3Event `json:"events"`
+}
+
+type json3Event struct {
+	TStartMs    float64    `json:"tStartMs"`
+	DDurationMs float64    `json:"dDurationMs"`
+	Segs        []json3Seg `json:"segs"`
+}
+
+type json3Seg struct {
+	UTF8      string  `json:"utf8"`
+	TOffsetMs float64 `json:"tOffsetMs"`
+}
+
+// parseJSON3Transcript parses YouTube's `fmt=json3` timedtext format, which
+// carries per-word timing via each event's segs.
+func parseJSON3Transcript(data string) ([]TranscriptEntry, error) {
+	var transcript json3Transcript
+	if err := json.Unmarshal([]byte(data), &transcript); err != nil {
+		return nil, err
+	}
+
+	entries := make([]TranscriptEntry, 0, len(transcript.Events))
+	for _, event := range transcript.Events {
+		if len(event.Segs) == 0 {
+			continue
+		}
+
+		var sb strings.Builder
+		words := make([]Word, 0, len(event.Segs))
+		for i, seg := range event.Segs {
+			sb.WriteString(seg.UTF8)
+
+			wordStartMs := event.TStartMs + seg.TOffsetMs
+			wordEndMs := event.TStartMs + event.DDurationMs
+			if i+1 < len(event.Segs) {
+				wordEndMs = event.TStartMs + event.Segs[i+1].TOffsetMs
+			}
+
+			words = append(words, Word{
+				Text:     seg.UTF8,
+				Start:    wordStartMs / 1000,
+				Duration: (wordEndMs - wordStartMs) / 1000,
+			})
+		}
+
+		entries = append(entries, TranscriptEntry{
+			Text:     sb.String(),
+			Start:    event.TStartMs / 1000,
+			Duration: event.DDurationMs / 1000,
+			Words:    words,
+		})
+	}
+
+	return entries, nil
+}
+
+// MergeConsecutive combines contiguous entries whose gap is smaller than
+// maxGap into single paragraph entries, joining their text with a newline.
+// An entry that starts before the previous one ends (a negative gap) is not
+// merged.
+func MergeConsecutive(entries []TranscriptEntry, maxGap time.Duration) []TranscriptEntry {
+	if len(entries) == 0 {
+		return entries
+	}
+
+	maxGapSeconds := maxGap.Seconds()
+	merged := []TranscriptEntry{entries[0]}
+
+	for _, entry := range entries[1:] {
+		last := &merged[len(merged)-1]
+		gap := entry.Start - (last.Start + last.Duration)
+
+		if gap >= 0 && gap < maxGapSeconds {
+			last.Text += "\n" + entry.Text
+			last.Duration = entry.Start + entry.Duration - last.Start
+			last.Styles = append(last.Styles, entry.Styles...)
+			last.Words = append(last.Words, entry.Words...)
+			continue
+		}
+
+		merged = append(merged, entry)
+	}
+
+	return merged
+}
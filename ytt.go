@@ -11,6 +11,11 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "playlist" {
+		runPlaylist(os.Args[2:])
+		return
+	}
+
 	flag.Usage = func() {
 		fmt.Printf("%s <youtube_url>\n", os.Args[0])
 		flag.PrintDefaults()
@@ -20,6 +25,11 @@ func main() {
 	noTimestamps := flag.Bool("no-timestamps", false, "Don't print timestamps")
 	filepath := flag.String("o", "", "Output filename (defaults to stdout)")
 	lang := flag.String("lang", "en", "Language code for the desired transcript")
+	format := flag.String("format", "text", "Output format: text, srt, vtt, or json")
+	translate := flag.String("translate", "", "Translate the transcript into this language code")
+	cookies := flag.String("cookies", "", "Path to a cookies.txt file (Netscape format) for age-restricted videos")
+	proxy := flag.String("proxy", "", "Proxy URL to route requests through")
+	userAgent := flag.String("user-agent", "", "Custom User-Agent header")
 
 	// Parse flags
 	flag.Parse()
@@ -30,13 +40,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	client, err := NewClient(ClientOptions{
+		CookiesPath: *cookies,
+		ProxyURL:    *proxy,
+		UserAgent:   *userAgent,
+	})
+	if err != nil {
+		printFancyError(fmt.Sprintf("failed to create client: %v", err))
+		os.Exit(1)
+	}
+
 	videoID, err := ExtractVideoID(flag.Arg(0))
 	if err != nil {
 		printFancyError(fmt.Sprintf("failed to extract video ID: %v", err))
 		os.Exit(1)
 	}
 
-	transcriptList, err := ListTranscripts(videoID)
+	transcriptList, err := client.ListTranscripts(videoID)
 	if err != nil {
 		printFancyError(fmt.Sprintf("failed to list transcripts: %v", err))
 		os.Exit(1)
@@ -68,23 +88,46 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *translate != "" {
+		transcript, err = transcript.Translate(*translate)
+		if err != nil {
+			printFancyError(fmt.Sprintf("failed to translate transcript to '%s': %v", *translate, err))
+			os.Exit(1)
+		}
+	}
+
 	entries, err := transcript.Fetch()
 	if err != nil {
 		printFancyError(fmt.Sprintf("Failed to fetch transcript: %v", err))
 		os.Exit(1)
 	}
 
-	var sb strings.Builder
-	for _, entry := range entries {
-		if !*noTimestamps {
+	for i := range entries {
+		entries[i].Text = html.UnescapeString(entries[i].Text)
+	}
+
+	var output string
+	if *format == "text" && !*noTimestamps {
+		var sb strings.Builder
+		for _, entry := range entries {
 			sb.WriteString(fmt.Sprintf("%.2f:%.2f\t", entry.Start, entry.Start+entry.Duration))
+			sb.WriteString(entry.Text)
+			sb.WriteString("\n")
+		}
+		output = sb.String()
+	} else {
+		formatter, err := NewFormatter(*format)
+		if err != nil {
+			printFancyError(fmt.Sprintf("invalid -format %q", *format))
+			os.Exit(1)
+		}
+		output, err = formatter.Format(entries)
+		if err != nil {
+			printFancyError(fmt.Sprintf("failed to format transcript: %v", err))
+			os.Exit(1)
 		}
-		sb.WriteString(html.UnescapeString(entry.Text))
-		sb.WriteString("\n")
 	}
 
-	output := sb.String()
-
 	if *filepath != "" {
 		err = os.WriteFile(*filepath, []byte(output), 0644)
 		if err != nil {